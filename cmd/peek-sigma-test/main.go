@@ -0,0 +1,79 @@
+// Command peek-sigma-test runs a single JSON event line through a loaded
+// Sigma ruleset and prints any hits, so rulesets can be exercised in CI
+// without standing up the full ingest/enrich pipeline.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"go-peek/pkg/models/events"
+	"go-peek/pkg/sigma"
+)
+
+var (
+	mainFlags = flag.NewFlagSet("main", flag.ExitOnError)
+	ruleset   = mainFlags.String("ruleset", "",
+		`Ruleset kind:path pairs, comma separated, e.g. "suricata:rules/net.yml"`)
+	kind = mainFlags.String("kind", "",
+		`events.Atomic kind of the input event, e.g. "suricata"`)
+	eventPath = mainFlags.String("event", "",
+		`Path to a file containing a single JSON event, or "-" for stdin`)
+)
+
+func main() {
+	mainFlags.Parse(os.Args[1:])
+
+	if *ruleset == "" || *kind == "" || *eventPath == "" {
+		fmt.Fprintln(os.Stderr, "ruleset, kind and event are required")
+		mainFlags.Usage()
+		os.Exit(1)
+	}
+
+	rs, err := sigma.New(sigma.Config{RulesetPaths: splitRulesets(*ruleset)})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+	defer rs.Close()
+
+	raw, err := readEvent(*eventPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	hits, err := rs.MatchRaw(raw, events.Atomic(*kind))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+	if len(hits) == 0 {
+		fmt.Fprintln(os.Stdout, "no matches")
+		return
+	}
+	for _, hit := range hits {
+		fmt.Fprintf(os.Stdout, "%s\t%s\n", hit.RuleID, hit.Title)
+	}
+}
+
+func readEvent(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+func splitRulesets(csv string) []string {
+	var out []string
+	start := 0
+	for i, r := range csv {
+		if r == ',' {
+			out = append(out, csv[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, csv[start:])
+}
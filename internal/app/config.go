@@ -18,20 +18,43 @@ const (
 	FlagInKafkaTopicMapper = "input-kafka-topic-map"
 	FlagInKafkaTopicAssets = "input-kafka-topic-assets"
 
+	// Kafka copartitioning
+	FlagInKafkaCopartitionGroups = "input-kafka-copartition-groups"
+
 	// Kafka Output
-	FlagOutKafkaEnabled = "output-kafka-enabled"
-	FlagOutKafkaTopic   = "output-kafka-topic"
-	FlagOutKafkaBrokers = "output-kafka-brokers"
+	FlagOutKafkaEnabled     = "output-kafka-enabled"
+	FlagOutKafkaTopic       = "output-kafka-topic"
+	FlagOutKafkaBrokers     = "output-kafka-brokers"
+	FlagOutKafkaCompression = "output-kafka-compression"
 
 	// Elastic Output
-	FlagOutElasticHosts  = "output-elastic-hosts"
-	FlagOutElasticPrefix = "output-elastic-prefix"
+	FlagOutElasticEnabled = "output-elastic-enabled"
+	FlagOutElasticHosts   = "output-elastic-hosts"
+	FlagOutElasticPrefix  = "output-elastic-prefix"
+
+	// File Output
+	FlagOutFileEnabled     = "output-file-enabled"
+	FlagOutFilePath        = "output-file-path"
+	FlagOutFileMaxBytes    = "output-file-max-bytes"
+	FlagOutFileRotateDaily = "output-file-rotate-daily"
+
+	// Stdout Output
+	FlagOutStdoutEnabled = "output-stdout-enabled"
+
+	// Output retry
+	FlagOutRetryAttempts = "output-retry-attempts"
+	FlagOutRetryBackoff  = "output-retry-backoff"
 
 	// Logging flags
 	FlagLogInterval = "log-interval"
 
 	// Sigma flags
 	FlagSigmaRulesetPaths = "sigma-ruleset-path"
+
+	// WISE server flags
+	FlagWiseServerEnabled = "wise-server-enabled"
+	FlagWiseServerListen  = "wise-server-listen"
+	FlagWiseServerFields  = "wise-server-fields"
 )
 
 func RegisterOutputKafka(prefix string, pFlags *pflag.FlagSet) {
@@ -43,9 +66,15 @@ func RegisterOutputKafka(prefix string, pFlags *pflag.FlagSet) {
 
 	pFlags.StringSlice(FlagOutKafkaBrokers, []string{"localhost:9092"}, "Kafka output broker list")
 	viper.BindPFlag(prefix+".output.kafka.brokers", pFlags.Lookup(FlagOutKafkaBrokers))
+
+	pFlags.String(FlagOutKafkaCompression, "none", "Kafka output compression codec (none, snappy, lz4)")
+	viper.BindPFlag(prefix+".output.kafka.compression", pFlags.Lookup(FlagOutKafkaCompression))
 }
 
 func RegisterOutputElastic(prefix string, pFlags *pflag.FlagSet) {
+	pFlags.Bool(FlagOutElasticEnabled, false, "Enable elasticsearch output")
+	viper.BindPFlag(prefix+".output.elasticsearch.enabled", pFlags.Lookup(FlagOutElasticEnabled))
+
 	pFlags.StringSlice(FlagOutElasticHosts, []string{"http://localhost:9200"}, "List of elastic hosts. Needs http:// prefix.")
 	viper.BindPFlag(prefix+".output.elasticsearch.hosts", pFlags.Lookup(FlagOutElasticHosts))
 
@@ -53,6 +82,35 @@ func RegisterOutputElastic(prefix string, pFlags *pflag.FlagSet) {
 	viper.BindPFlag(prefix+".output.elasticsearch.prefix", pFlags.Lookup(FlagOutElasticPrefix))
 }
 
+func RegisterOutputFile(prefix string, pFlags *pflag.FlagSet) {
+	pFlags.Bool(FlagOutFileEnabled, false, "Enable file output")
+	viper.BindPFlag(prefix+".output.file.enabled", pFlags.Lookup(FlagOutFileEnabled))
+
+	pFlags.String(FlagOutFilePath, "peek.log", "File output path")
+	viper.BindPFlag(prefix+".output.file.path", pFlags.Lookup(FlagOutFilePath))
+
+	pFlags.Int64(FlagOutFileMaxBytes, 0, "Rotate file output once it exceeds this many bytes, 0 disables size rotation")
+	viper.BindPFlag(prefix+".output.file.max_bytes", pFlags.Lookup(FlagOutFileMaxBytes))
+
+	pFlags.Bool(FlagOutFileRotateDaily, false, "Rotate file output at UTC midnight")
+	viper.BindPFlag(prefix+".output.file.rotate_daily", pFlags.Lookup(FlagOutFileRotateDaily))
+}
+
+func RegisterOutputStdout(prefix string, pFlags *pflag.FlagSet) {
+	pFlags.Bool(FlagOutStdoutEnabled, false, "Enable stdout output")
+	viper.BindPFlag(prefix+".output.stdout.enabled", pFlags.Lookup(FlagOutStdoutEnabled))
+}
+
+// RegisterOutputRetry registers the attempt/backoff flags outputs.Build
+// uses to wrap every constructed sink in a WithRetry.
+func RegisterOutputRetry(prefix string, pFlags *pflag.FlagSet) {
+	pFlags.Int(FlagOutRetryAttempts, 3, "Number of attempts at a sink write, the first one included, before giving up")
+	viper.BindPFlag(prefix+".output.retry.attempts", pFlags.Lookup(FlagOutRetryAttempts))
+
+	pFlags.Duration(FlagOutRetryBackoff, 500*time.Millisecond, "Initial backoff between sink write retries, doubled on every attempt")
+	viper.BindPFlag(prefix+".output.retry.backoff", pFlags.Lookup(FlagOutRetryBackoff))
+}
+
 func RegisterInputKafkaGenericSimple(prefix string, pFlags *pflag.FlagSet) {
 	pFlags.StringSlice(FlagInKafkaTopics, []string{}, "List of input topics")
 	viper.BindPFlag(prefix+".input.kafka.topics", pFlags.Lookup(FlagInKafkaTopics))
@@ -74,6 +132,10 @@ func RegisterInputKafkaEnrich(prefix string, pFlags *pflag.FlagSet) {
 
 	pFlags.String(FlagInKafkaTopicAssets, "assets", "Topic that holds asset information")
 	viper.BindPFlag(prefix+".input.kafka.topic_assets", pFlags.Lookup(FlagInKafkaTopicAssets))
+
+	pFlags.StringSlice(FlagInKafkaCopartitionGroups, []string{},
+		"Topic groups that must be copartitioned, one group per entry, topics within a group separated by semicolon, e.g. \"suricata-alert;suricata-flow\"")
+	viper.BindPFlag(prefix+".input.kafka.copartition_groups", pFlags.Lookup(FlagInKafkaCopartitionGroups))
 }
 
 func RegisterSigmaRulesetPaths(prefix string, pFlags *pflag.FlagSet) {
@@ -81,6 +143,17 @@ func RegisterSigmaRulesetPaths(prefix string, pFlags *pflag.FlagSet) {
 	viper.BindPFlag(prefix+".sigma.ruleset_path", pFlags.Lookup(FlagSigmaRulesetPaths))
 }
 
+func RegisterWiseServer(prefix string, pFlags *pflag.FlagSet) {
+	pFlags.Bool(FlagWiseServerEnabled, false, "Enable WISE-compatible HTTP enrichment server")
+	viper.BindPFlag(prefix+".wise_server.enabled", pFlags.Lookup(FlagWiseServerEnabled))
+
+	pFlags.String(FlagWiseServerListen, ":8081", "WISE server listen address")
+	viper.BindPFlag(prefix+".wise_server.listen", pFlags.Lookup(FlagWiseServerListen))
+
+	pFlags.StringSlice(FlagWiseServerFields, []string{}, "WISE fields to return per hit, empty returns all known fields")
+	viper.BindPFlag(prefix+".wise_server.fields", pFlags.Lookup(FlagWiseServerFields))
+}
+
 func RegisterLogging(prefix string, pFlags *pflag.FlagSet) {
 	pFlags.Duration(FlagLogInterval, 30*time.Second, "periodic logging and report interval")
 	viper.BindPFlag(prefix+".log.interval", pFlags.Lookup(FlagLogInterval))
@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Shopify/sarama"
+)
+
+// NewCopartitionStrategy returns a sarama.BalanceStrategy that guarantees
+// partition N of every topic in a copartitioned group is assigned to the
+// same consumer group member, so per-partition joins (e.g. suricata alerts
+// and flows keyed by the same flow-id) never need a shuffle. Topics outside
+// every group fall back to sarama's range strategy.
+func NewCopartitionStrategy(groups [][]string) sarama.BalanceStrategy {
+	return &copartitionStrategy{groups: groups}
+}
+
+type copartitionStrategy struct {
+	groups [][]string
+}
+
+func (s *copartitionStrategy) Name() string { return "copartition" }
+
+func (s *copartitionStrategy) Plan(
+	members map[string]sarama.ConsumerGroupMemberMetadata,
+	topics map[string][]int32,
+) (sarama.BalanceStrategyPlan, error) {
+	plan := make(sarama.BalanceStrategyPlan, len(members))
+	memberIDs := sortedMemberIDs(members)
+	grouped := make(map[string]bool)
+
+	for _, group := range s.groups {
+		partitionCount := -1
+		for _, topic := range group {
+			partitions, ok := topics[topic]
+			if !ok {
+				return nil, fmt.Errorf("copartition strategy: topic %q not present in subscription", topic)
+			}
+			if partitionCount == -1 {
+				partitionCount = len(partitions)
+			} else if len(partitions) != partitionCount {
+				return nil, fmt.Errorf(
+					"copartition strategy: topic %q has %d partitions, expected %d to match the rest of its group",
+					topic, len(partitions), partitionCount)
+			}
+			grouped[topic] = true
+		}
+		if partitionCount <= 0 || len(memberIDs) == 0 {
+			continue
+		}
+
+		// Contiguous partition ranges, replicated across every topic in the
+		// group, so member i always owns the same partition number on every
+		// topic it is assigned.
+		perMember := partitionCount / len(memberIDs)
+		remainder := partitionCount % len(memberIDs)
+		next := 0
+		for i, memberID := range memberIDs {
+			count := perMember
+			if i < remainder {
+				count++
+			}
+			for _, topic := range group {
+				for p := next; p < next+count; p++ {
+					plan.Add(memberID, topic, int32(p))
+				}
+			}
+			next += count
+		}
+	}
+
+	ungrouped := make(map[string][]int32)
+	for topic, partitions := range topics {
+		if !grouped[topic] {
+			ungrouped[topic] = partitions
+		}
+	}
+	if len(ungrouped) > 0 {
+		fallbackPlan, err := sarama.BalanceStrategyRange.Plan(members, ungrouped)
+		if err != nil {
+			return nil, err
+		}
+		for memberID, topicPartitions := range fallbackPlan {
+			for topic, partitions := range topicPartitions {
+				for _, p := range partitions {
+					plan.Add(memberID, topic, p)
+				}
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func sortedMemberIDs(members map[string]sarama.ConsumerGroupMemberMetadata) []string {
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
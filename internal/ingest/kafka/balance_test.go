@@ -0,0 +1,102 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+)
+
+func membersMeta(ids ...string) map[string]sarama.ConsumerGroupMemberMetadata {
+	members := make(map[string]sarama.ConsumerGroupMemberMetadata, len(ids))
+	for _, id := range ids {
+		members[id] = sarama.ConsumerGroupMemberMetadata{}
+	}
+	return members
+}
+
+func partitions(n int) []int32 {
+	out := make([]int32, n)
+	for i := range out {
+		out[i] = int32(i)
+	}
+	return out
+}
+
+// planTopic returns the sorted partitions plan assigns member to on topic.
+func planTopic(plan sarama.BalanceStrategyPlan, member, topic string) []int32 {
+	return plan[member][topic]
+}
+
+func TestCopartitionStrategyEvenSplit(t *testing.T) {
+	strategy := NewCopartitionStrategy([][]string{{"alert", "flow"}})
+	topics := map[string][]int32{
+		"alert": partitions(4),
+		"flow":  partitions(4),
+	}
+	plan, err := strategy.Plan(membersMeta("a", "b"), topics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, member := range []string{"a", "b"} {
+		alert := planTopic(plan, member, "alert")
+		flow := planTopic(plan, member, "flow")
+		if len(alert) != 2 || len(flow) != 2 {
+			t.Fatalf("member %q: want 2 partitions per topic, got alert=%v flow=%v", member, alert, flow)
+		}
+		for i := range alert {
+			if alert[i] != flow[i] {
+				t.Fatalf("member %q: alert and flow partitions diverge: %v vs %v", member, alert, flow)
+			}
+		}
+	}
+}
+
+func TestCopartitionStrategyRemainder(t *testing.T) {
+	strategy := NewCopartitionStrategy([][]string{{"alert"}})
+	topics := map[string][]int32{"alert": partitions(5)}
+
+	plan, err := strategy.Plan(membersMeta("a", "b"), topics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	total := len(planTopic(plan, "a", "alert")) + len(planTopic(plan, "b", "alert"))
+	if total != 5 {
+		t.Fatalf("want all 5 partitions assigned, got %d", total)
+	}
+	// The lexically-first member id absorbs the remainder partition.
+	if got := len(planTopic(plan, "a", "alert")); got != 3 {
+		t.Errorf("want member %q to get 3 partitions, got %d", "a", got)
+	}
+}
+
+func TestCopartitionStrategyMismatchedPartitionCounts(t *testing.T) {
+	strategy := NewCopartitionStrategy([][]string{{"alert", "flow"}})
+	topics := map[string][]int32{
+		"alert": partitions(4),
+		"flow":  partitions(3),
+	}
+	if _, err := strategy.Plan(membersMeta("a"), topics); err == nil {
+		t.Fatal("want error on mismatched partition counts within a group, got nil")
+	}
+}
+
+func TestCopartitionStrategyUngroupedTopicFallsBackToRange(t *testing.T) {
+	strategy := NewCopartitionStrategy([][]string{{"alert"}})
+	topics := map[string][]int32{
+		"alert": partitions(2),
+		"other": partitions(2),
+	}
+	plan, err := strategy.Plan(membersMeta("a", "b"), topics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var total int
+	for _, member := range []string{"a", "b"} {
+		total += len(planTopic(plan, member, "other"))
+	}
+	if total != 2 {
+		t.Fatalf("want ungrouped topic fully assigned via fallback, got %d partitions", total)
+	}
+}
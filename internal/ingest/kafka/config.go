@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"go-peek/pkg/models/events"
+)
+
+// KafkaConfig configures a KafkaIngest consumer group member.
+type KafkaConfig struct {
+	Brokers       []string
+	Topics        []string
+	ConsumerGroup string
+	SaramaConfig  *sarama.Config
+
+	// TopicKinds resolves each consumed topic to the events.Atomic kind
+	// its payloads decode as, so callers no longer need to pass a kind
+	// alongside every message. Populate via ParseTopicKinds.
+	TopicKinds map[string]events.Atomic
+
+	// CopartitionGroups lists sets of topics that must have partition N
+	// assigned to the same consumer group member on every topic in the
+	// set, e.g. suricata alerts and flows keyed by the same flow-id. Topics
+	// not part of any group fall back to sarama's range strategy.
+	CopartitionGroups [][]string
+
+	// OnAssign and OnRevoke are invoked with the session's claimed
+	// partitions from ConsumerGroupHandler.Setup and Cleanup respectively,
+	// so callers can prime or flush per-partition state around a rebalance.
+	OnAssign func(claims map[string][]int32)
+	OnRevoke func(claims map[string][]int32)
+}
+
+func (c KafkaConfig) Validate() error {
+	if len(c.Brokers) == 0 {
+		return errors.New("kafka ingest missing brokers")
+	}
+	if len(c.Topics) == 0 {
+		return errors.New("kafka ingest missing topics")
+	}
+	if c.ConsumerGroup == "" {
+		return errors.New("kafka ingest missing consumer group")
+	}
+	return nil
+}
+
+// ParseTopicKinds turns "topic:kind" pairs, as produced by
+// app.FlagInKafkaTopicMapper, into a TopicKinds map.
+func ParseTopicKinds(pairs []string) (map[string]events.Atomic, error) {
+	out := make(map[string]events.Atomic, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed topic:kind pair %q", pair)
+		}
+		out[parts[0]] = events.Atomic(parts[1])
+	}
+	return out, nil
+}
+
+// ParseCopartitionGroups turns app.FlagInKafkaCopartitionGroups entries,
+// one group per entry with topics separated by semicolons, into
+// CopartitionGroups.
+func ParseCopartitionGroups(entries []string) [][]string {
+	groups := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		groups = append(groups, strings.Split(entry, ";"))
+	}
+	return groups
+}
+
+// NewConsumerConfig returns sane defaults for a consumer group member
+// running against a Kafka 2.x broker.
+func NewConsumerConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_0_0_0
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	return config
+}
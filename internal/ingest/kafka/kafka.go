@@ -2,75 +2,129 @@ package kafka
 
 import (
 	"context"
-	"fmt"
-	"os"
 
-	cluster "github.com/bsm/sarama-cluster"
+	"github.com/Shopify/sarama"
 	"github.com/ccdcoe/go-peek/internal/ingest/message"
+	log "github.com/sirupsen/logrus"
 )
 
+// KafkaIngest consumes one or more kafka topics as a member of a consumer
+// group and republishes raw payloads on Messages(). Group membership,
+// partition assignment and offset commits are all handled by Sarama's
+// native consumer group implementation; KafkaIngest only needs to keep
+// re-joining the group for the consume loop to survive rebalances.
 type KafkaIngest struct {
 	output chan message.Message
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	*cluster.Consumer
+	group  sarama.ConsumerGroup
+	config *KafkaConfig
 }
 
 func NewKafkaIngest(config *KafkaConfig) (*KafkaIngest, error) {
-	var (
-		err error
-		k   = &KafkaIngest{
-			output: make(chan message.Message, 0),
-		}
-	)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 	if config.SaramaConfig == nil {
 		config.SaramaConfig = NewConsumerConfig()
 	}
-	fmt.Println(config.Topics)
-	if k.Consumer, err = cluster.NewConsumer(
-		config.Brokers,
-		config.ConsumerGroup,
-		config.Topics,
-		config.SaramaConfig,
-	); err != nil {
+	if len(config.CopartitionGroups) > 0 {
+		config.SaramaConfig.Consumer.Group.Rebalance.Strategy = NewCopartitionStrategy(config.CopartitionGroups)
+	}
+	group, err := sarama.NewConsumerGroup(config.Brokers, config.ConsumerGroup, config.SaramaConfig)
+	if err != nil {
 		return nil, err
 	}
+
+	k := &KafkaIngest{
+		output: make(chan message.Message, 0),
+		group:  group,
+		config: config,
+	}
 	k.ctx, k.cancel = context.WithCancel(context.Background())
-	go func() {
-	loop:
-		for {
-			select {
-			case msg, ok := <-k.Consumer.Messages():
-				if !ok {
-					break loop
-				}
-				k.output <- message.Message{
-					Data:   msg.Value,
-					Offset: msg.Offset,
-					Source: msg.Topic,
-				}
-			case <-k.ctx.Done():
-				k.Consumer.Close()
-			}
-		}
-	}()
 
-	// *TODO* Move to separate notification handler
 	go func() {
-		for not := range k.Notifications() {
-			fmt.Fprintf(os.Stdout, "%+v\n", not)
+		for err := range k.group.Errors() {
+			log.Errorf("kafka consumer group %s error: %s", config.ConsumerGroup, err)
 		}
 	}()
 
+	go k.consumeLoop()
+
 	return k, nil
 }
 
-func (k KafkaIngest) Messages() <-chan message.Message {
+// consumeLoop re-joins the consumer group whenever Consume returns, which
+// happens on every rebalance as well as on transient broker errors. It
+// exits only once the ingest's own context has been cancelled via Halt.
+func (k *KafkaIngest) consumeLoop() {
+	handler := &groupHandler{out: k.output, config: k.config}
+	for k.ctx.Err() == nil {
+		if err := k.group.Consume(k.ctx, k.config.Topics, handler); err != nil {
+			if err == sarama.ErrClosedConsumerGroup {
+				return
+			}
+			log.Errorf("kafka consumer group %s consume error, rejoining: %s", k.config.ConsumerGroup, err)
+		}
+	}
+}
+
+func (k *KafkaIngest) Messages() <-chan message.Message {
 	return k.output
 }
 
-func (k KafkaIngest) Halt() error {
+func (k *KafkaIngest) Halt() error {
 	k.cancel()
-	return k.ctx.Err()
+	return k.group.Close()
+}
+
+// groupHandler implements sarama.ConsumerGroupHandler, bridging claimed
+// partitions onto the shared output channel and marking messages consumed
+// as they are forwarded.
+type groupHandler struct {
+	out    chan message.Message
+	config *KafkaConfig
+}
+
+func (h *groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	if h.config.OnAssign != nil {
+		h.config.OnAssign(session.Claims())
+	}
+	return nil
+}
+
+func (h *groupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	if h.config.OnRevoke != nil {
+		h.config.OnRevoke(session.Claims())
+	}
+	return nil
+}
+
+func (h *groupHandler) ConsumeClaim(
+	session sarama.ConsumerGroupSession,
+	claim sarama.ConsumerGroupClaim,
+) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			select {
+			case h.out <- message.Message{
+				Data:      msg.Value,
+				Offset:    msg.Offset,
+				Partition: msg.Partition,
+				Source:    msg.Topic,
+				Kind:      h.config.TopicKinds[msg.Topic],
+			}:
+				session.MarkMessage(msg, "")
+			case <-session.Context().Done():
+				return nil
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
 }
@@ -0,0 +1,18 @@
+package message
+
+import "go-peek/pkg/models/events"
+
+// Message is a generic container for a single raw record pulled off an
+// ingest source (kafka, file, ...) before it is decoded into a concrete
+// events.GameEvent.
+type Message struct {
+	Data      []byte
+	Offset    int64
+	Partition int32
+	Source    string
+
+	// Kind is the events.Atomic the ingest source resolved Source (e.g. a
+	// kafka topic) to, sparing callers from having to pass a kind
+	// alongside every message.
+	Kind events.Atomic
+}
@@ -0,0 +1,68 @@
+package outputs
+
+import (
+	"time"
+
+	"github.com/ccdcoe/go-peek/internal/outputs/elastic"
+	"github.com/ccdcoe/go-peek/internal/outputs/file"
+	"github.com/ccdcoe/go-peek/internal/outputs/kafka"
+	"github.com/ccdcoe/go-peek/internal/outputs/stdout"
+	"github.com/spf13/viper"
+)
+
+// Build assembles a MultiSink from whichever `<prefix>.output.<name>.enabled`
+// flags are set, reading the rest of each sink's configuration from the
+// same viper namespace populated by app.RegisterOutput*. Every constructed
+// sink is wrapped in WithRetry using app.RegisterOutputRetry's flags, so a
+// transient write failure on one sink doesn't need to be handled by every
+// caller of Build.
+func Build(prefix string) (Sink, error) {
+	var sinks MultiSink
+
+	attempts := viper.GetInt(prefix + ".output.retry.attempts")
+	if attempts <= 0 {
+		attempts = 3
+	}
+	backoff := viper.GetDuration(prefix + ".output.retry.backoff")
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+
+	if viper.GetBool(prefix + ".output.kafka.enabled") {
+		sink, err := kafka.New(kafka.Config{
+			Brokers:     viper.GetStringSlice(prefix + ".output.kafka.brokers"),
+			Topic:       viper.GetString(prefix + ".output.kafka.topic"),
+			Compression: viper.GetString(prefix + ".output.kafka.compression"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, WithRetry(sink, attempts, backoff))
+	}
+	if viper.GetBool(prefix + ".output.elasticsearch.enabled") {
+		sink, err := elastic.New(elastic.Config{
+			Hosts:  viper.GetStringSlice(prefix + ".output.elasticsearch.hosts"),
+			Prefix: viper.GetString(prefix + ".output.elasticsearch.prefix"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, WithRetry(sink, attempts, backoff))
+	}
+	if viper.GetBool(prefix + ".output.file.enabled") {
+		sink, err := file.New(file.Config{
+			Path:        viper.GetString(prefix + ".output.file.path"),
+			MaxBytes:    viper.GetInt64(prefix + ".output.file.max_bytes"),
+			RotateDaily: viper.GetBool(prefix + ".output.file.rotate_daily"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, WithRetry(sink, attempts, backoff))
+	}
+	if viper.GetBool(prefix + ".output.stdout.enabled") {
+		sinks = append(sinks, WithRetry(stdout.New(), attempts, backoff))
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,124 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ccdcoe/go-peek/internal/ingest/message"
+	elastic "github.com/olivere/elastic/v7"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config configures an elasticsearch bulk-indexing Sink.
+type Config struct {
+	Hosts []string
+	// Prefix is prepended to the daily rollover index name, e.g.
+	// "<prefix>-2020.01.02".
+	Prefix string
+}
+
+func (c Config) Validate() error {
+	if len(c.Hosts) == 0 {
+		return errors.New("elastic output missing hosts")
+	}
+	if c.Prefix == "" {
+		return errors.New("elastic output missing index prefix")
+	}
+	return nil
+}
+
+// Sink bulk-indexes messages into day-rolled elasticsearch indices, backed
+// by the olivere/elastic client's BulkProcessor for batching and backoff.
+type Sink struct {
+	config Config
+	client *elastic.Client
+	bulk   *elastic.BulkProcessor
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+func New(c Config) (*Sink, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := elastic.NewClient(elastic.SetURL(c.Hosts...))
+	if err != nil {
+		return nil, err
+	}
+	s := &Sink{config: c, client: client}
+	bulk, err := client.BulkProcessor().
+		Workers(2).
+		BulkActions(1000).
+		FlushInterval(5 * time.Second).
+		Backoff(elastic.NewExponentialBackoff(100*time.Millisecond, 8*time.Second)).
+		After(s.afterBulk).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	s.bulk = bulk
+	return s, nil
+}
+
+func (s Sink) index() string {
+	return fmt.Sprintf("%s-%s", s.config.Prefix, time.Now().UTC().Format("2006.01.02"))
+}
+
+func (s *Sink) Write(ctx context.Context, batch []message.Message) error {
+	for _, m := range batch {
+		s.bulk.Add(elastic.NewBulkIndexRequest().
+			Index(s.index()).
+			Doc(json.RawMessage(m.Data)))
+	}
+	// The bulk processor executes on its own schedule, well after Add
+	// returns, so the most a Write can do is surface whatever the previous
+	// execution's afterBulk callback recorded rather than claim success it
+	// has no way to know about yet.
+	return s.takeErr()
+}
+
+// afterBulk is the BulkProcessor's completion callback. It logs every
+// transport-level or per-document failure and records the most recent one
+// so the next Write (and so outputs.WithRetry) can see it.
+func (s *Sink) afterBulk(executionID int64, requests []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+	if err != nil {
+		log.WithError(err).Error("elastic output: bulk execution failed")
+		s.setErr(err)
+		return
+	}
+	if response == nil || !response.Errors {
+		return
+	}
+	failed := response.Failed()
+	for _, item := range failed {
+		log.Errorf("elastic output: failed to index document into %q: %+v", item.Index, item.Error)
+	}
+	s.setErr(fmt.Errorf("elastic output: %d document(s) failed to index", len(failed)))
+}
+
+func (s *Sink) setErr(err error) {
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+}
+
+func (s *Sink) takeErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.lastErr
+	s.lastErr = nil
+	return err
+}
+
+func (s *Sink) Flush(ctx context.Context) error {
+	return s.bulk.Flush()
+}
+
+func (s *Sink) Close() error {
+	return s.bulk.Close()
+}
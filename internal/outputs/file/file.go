@@ -0,0 +1,119 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ccdcoe/go-peek/internal/ingest/message"
+)
+
+// Config configures a rotated-file Sink.
+type Config struct {
+	Path string
+	// MaxBytes rotates the file once it would exceed this size. Zero
+	// disables size-based rotation.
+	MaxBytes int64
+	// RotateDaily rotates the file at UTC midnight regardless of size.
+	RotateDaily bool
+}
+
+func (c Config) Validate() error {
+	if c.Path == "" {
+		return errors.New("file output missing path")
+	}
+	return nil
+}
+
+// Sink appends messages to a plain file, rotating it to numbered
+// `<path>.NNN` backups (log4go style) once it grows past MaxBytes or rolls
+// over a UTC day boundary.
+type Sink struct {
+	config Config
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	day  string
+}
+
+func New(c Config) (*Sink, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Sink{config: c}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Sink) open() error {
+	f, err := os.OpenFile(s.config.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file = f
+	s.size = stat.Size()
+	s.day = time.Now().UTC().Format("2006-01-02")
+	return nil
+}
+
+// rotate renames the current file to the first free `<path>.NNN` suffix
+// and reopens a fresh file at the original path.
+func (s *Sink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s.%03d", s.config.Path, n)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			if err := os.Rename(s.config.Path, candidate); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return s.open()
+}
+
+func (s *Sink) Write(ctx context.Context, batch []message.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range batch {
+		today := time.Now().UTC().Format("2006-01-02")
+		needsRotate := (s.config.RotateDaily && today != s.day) ||
+			(s.config.MaxBytes > 0 && s.size+int64(len(m.Data))+1 > s.config.MaxBytes)
+		if needsRotate {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := fmt.Fprintf(s.file, "%s\n", m.Data)
+		if err != nil {
+			return err
+		}
+		s.size += int64(n)
+	}
+	return nil
+}
+
+func (s *Sink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Sync()
+}
+
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
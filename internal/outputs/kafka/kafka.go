@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shopify/sarama"
+	"github.com/ccdcoe/go-peek/internal/ingest/message"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config configures a kafka producer Sink.
+type Config struct {
+	Brokers []string
+	Topic   string
+	// Compression is one of "none" (default), "snappy" or "lz4".
+	Compression string
+	// Async switches the sink to a fire-and-forget AsyncProducer instead of
+	// the default SyncProducer.
+	Async bool
+}
+
+func (c Config) Validate() error {
+	if len(c.Brokers) == 0 {
+		return errors.New("kafka output missing brokers")
+	}
+	if c.Topic == "" {
+		return errors.New("kafka output missing topic")
+	}
+	return nil
+}
+
+func (c Config) compression() sarama.CompressionCodec {
+	switch c.Compression {
+	case "snappy":
+		return sarama.CompressionSnappy
+	case "lz4":
+		return sarama.CompressionLZ4
+	default:
+		return sarama.CompressionNone
+	}
+}
+
+// Sink publishes messages to a single kafka topic.
+type Sink struct {
+	config Config
+
+	sync  sarama.SyncProducer
+	async sarama.AsyncProducer
+}
+
+func New(c Config) (*Sink, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Compression = c.compression()
+
+	s := &Sink{config: c}
+	if c.Async {
+		producer, err := sarama.NewAsyncProducer(c.Brokers, saramaConfig)
+		if err != nil {
+			return nil, err
+		}
+		s.async = producer
+		go func() {
+			for range producer.Successes() {
+			}
+		}()
+		go func() {
+			for err := range producer.Errors() {
+				log.WithError(err.Err).Errorf("kafka output: failed to produce to topic %q", c.Topic)
+			}
+		}()
+		return s, nil
+	}
+	producer, err := sarama.NewSyncProducer(c.Brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	s.sync = producer
+	return s, nil
+}
+
+func (s *Sink) Write(ctx context.Context, batch []message.Message) error {
+	for _, m := range batch {
+		msg := &sarama.ProducerMessage{
+			Topic: s.config.Topic,
+			Value: sarama.ByteEncoder(m.Data),
+		}
+		if s.async != nil {
+			select {
+			case s.async.Input() <- msg:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+		if _, _, err := s.sync.SendMessage(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sink) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (s *Sink) Close() error {
+	if s.async != nil {
+		return s.async.Close()
+	}
+	return s.sync.Close()
+}
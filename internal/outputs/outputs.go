@@ -0,0 +1,91 @@
+package outputs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ccdcoe/go-peek/internal/ingest/message"
+)
+
+// Sink ships a batch of messages to a downstream system. Implementations
+// must be safe for concurrent use by a single writer goroutine; MultiSink
+// fans a batch out to several Sinks without additional locking.
+type Sink interface {
+	Write(ctx context.Context, batch []message.Message) error
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+// MultiSink fans a single Write/Flush/Close out to every member sink,
+// collecting errors from all of them rather than failing fast on the
+// first one.
+type MultiSink []Sink
+
+func (m MultiSink) Write(ctx context.Context, batch []message.Message) error {
+	var errs []string
+	for _, sink := range m {
+		if err := sink.Write(ctx, batch); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrs(errs)
+}
+
+func (m MultiSink) Flush(ctx context.Context) error {
+	var errs []string
+	for _, sink := range m {
+		if err := sink.Flush(ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrs(errs)
+}
+
+func (m MultiSink) Close() error {
+	var errs []string
+	for _, sink := range m {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrs(errs)
+}
+
+func joinErrs(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("outputs: %s", strings.Join(errs, "; "))
+}
+
+// WithRetry wraps a Sink so that a failed Write is retried with exponential
+// backoff, doubling the wait on every attempt, up to attempts tries before
+// the last error is returned to the caller.
+func WithRetry(sink Sink, attempts int, initial time.Duration) Sink {
+	return &retrySink{Sink: sink, attempts: attempts, initial: initial}
+}
+
+type retrySink struct {
+	Sink
+	attempts int
+	initial  time.Duration
+}
+
+func (r *retrySink) Write(ctx context.Context, batch []message.Message) error {
+	wait := r.initial
+	var err error
+	for attempt := 0; attempt < r.attempts; attempt++ {
+		if err = r.Sink.Write(ctx, batch); err == nil {
+			return nil
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wait *= 2
+	}
+	return err
+}
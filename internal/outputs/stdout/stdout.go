@@ -0,0 +1,26 @@
+package stdout
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ccdcoe/go-peek/internal/ingest/message"
+)
+
+// Sink writes messages to stdout, one per line. Useful for local debugging
+// alongside or instead of the other output sinks.
+type Sink struct{}
+
+func New() *Sink { return &Sink{} }
+
+func (s *Sink) Write(ctx context.Context, batch []message.Message) error {
+	for _, m := range batch {
+		fmt.Fprintf(os.Stdout, "%s\n", m.Data)
+	}
+	return nil
+}
+
+func (s *Sink) Flush(ctx context.Context) error { return nil }
+
+func (s *Sink) Close() error { return nil }
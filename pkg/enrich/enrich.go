@@ -2,6 +2,7 @@ package enrich
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,10 @@ import (
 	"go-peek/pkg/models/meta"
 	"go-peek/pkg/persist"
 	"go-peek/pkg/providentia"
+	"go-peek/pkg/sigma"
+
+	"github.com/ccdcoe/go-peek/internal/ingest/message"
+	"github.com/ccdcoe/go-peek/internal/outputs"
 )
 
 const badgerPrefix = "assets"
@@ -22,8 +27,26 @@ func (e ErrMissingAssetData) Error() string {
 	return fmt.Sprintf("missing asset data for %+v", e.Event)
 }
 
+// Tagged wraps an event together with the IDs of every Sigma rule it
+// matched. Concrete event types don't carry a settable sigma-hit field, so
+// Decode attaches a match the same way the stream/join package attaches a
+// join partner: by embedding the original events.GameEvent, which promotes
+// its methods so a Tagged value still satisfies that interface.
+type Tagged struct {
+	events.GameEvent
+	SigmaHits []string
+}
+
 type Config struct {
 	Persist *persist.Badger
+	// Sigma is optional; when set, Decode runs every decoded event
+	// through it and records matches on Counts.SigmaHits.
+	Sigma *sigma.Ruleset
+	// Output is optional; when set, Enrich ships every successfully
+	// enriched event to it as a JSON-encoded message.Message. Build it
+	// with outputs.Build against the same viper prefix the caller
+	// registered app.RegisterOutput* flags under.
+	Output outputs.Sink
 }
 
 func (c Config) Validate() error {
@@ -42,6 +65,9 @@ type Counts struct {
 	Assets       int
 
 	ParseErrs countsParseErrs
+
+	SigmaHits    uint
+	SigmaPerRule map[string]uint
 }
 
 type countsParseErrs struct {
@@ -58,6 +84,16 @@ type Handler struct {
 
 	assets  map[string]providentia.Record
 	persist *persist.Badger
+	sigma   *sigma.Ruleset
+	output  outputs.Sink
+}
+
+// Asset looks up a known asset by IP or hostname key, for callers outside
+// this package (e.g. the WISE HTTP server) that need read access to the
+// handler's resolved asset inventory.
+func (h Handler) Asset(key string) (providentia.Record, bool) {
+	val, ok := h.assets[key]
+	return val, ok
 }
 
 func (h Handler) MissingKeys() []string {
@@ -82,6 +118,13 @@ func (h *Handler) AddAsset(value providentia.Record) *Handler {
 	return h
 }
 
+// DecodeMessage is Decode for callers consuming from an ingest source that
+// already resolved the message's kind (e.g. KafkaConfig.TopicKinds), so the
+// caller doesn't need to carry that mapping itself just to call Decode.
+func (h *Handler) DecodeMessage(msg message.Message) (events.GameEvent, error) {
+	return h.Decode(msg.Data, msg.Kind)
+}
+
 func (h *Handler) Decode(raw []byte, kind events.Atomic) (events.GameEvent, error) {
 	var event events.GameEvent
 	h.Counts.Events++
@@ -116,9 +159,40 @@ func (h *Handler) Decode(raw []byte, kind events.Atomic) (events.GameEvent, erro
 		}
 		event = &obj
 	}
+
+	if h.sigma != nil && event != nil {
+		tagged, err := h.matchSigma(event, kind)
+		if err != nil {
+			return event, err
+		}
+		event = tagged
+	}
+
 	return event, nil
 }
 
+// matchSigma runs event through the configured ruleset, tallies the hits on
+// Counts and, if any rule matched, returns event wrapped in a Tagged
+// carrying their rule IDs so sinks and the stream/join package downstream
+// can see which rule fired.
+func (h *Handler) matchSigma(event events.GameEvent, kind events.Atomic) (events.GameEvent, error) {
+	hits, err := h.sigma.Match(event, kind)
+	if err != nil {
+		return event, err
+	}
+	if len(hits) == 0 {
+		return event, nil
+	}
+
+	ruleIDs := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		h.Counts.SigmaHits++
+		h.Counts.SigmaPerRule[hit.RuleID]++
+		ruleIDs = append(ruleIDs, hit.RuleID)
+	}
+	return Tagged{GameEvent: event, SigmaHits: ruleIDs}, nil
+}
+
 func (h *Handler) Enrich(event events.GameEvent) error {
 	fullAsset := event.GetAsset()
 	if fullAsset == nil {
@@ -133,9 +207,24 @@ func (h *Handler) Enrich(event events.GameEvent) error {
 		fullAsset.Destination = h.assetLookup(*fullAsset.Destination)
 	}
 
+	if h.output != nil {
+		return h.ship(event)
+	}
 	return nil
 }
 
+// ship JSON-encodes an enriched event and writes it to the configured
+// output sink, so Config.Output (built with outputs.Build) is the single
+// place a caller needs to touch to fan enriched events out to kafka,
+// elasticsearch, file or stdout sinks.
+func (h *Handler) ship(event events.GameEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return h.output.Write(context.Background(), []message.Message{{Data: raw}})
+}
+
 func (h Handler) assetLookup(asset meta.Asset) *meta.Asset {
 	if asset.IP != nil {
 		if val, ok := h.assets[asset.IP.String()]; ok {
@@ -152,6 +241,9 @@ func (h Handler) assetLookup(asset meta.Asset) *meta.Asset {
 }
 
 func (h *Handler) Close() error {
+	if h.output != nil {
+		return h.output.Close()
+	}
 	return nil
 }
 
@@ -175,7 +267,12 @@ func NewHandler(c Config) (*Handler, error) {
 	return &Handler{
 		persist:          c.Persist,
 		assets:           assets,
+		sigma:            c.Sigma,
+		output:           c.Output,
 		missingLookupSet: make(map[string]bool),
-		Counts:           Counts{Assets: len(assets)},
+		Counts: Counts{
+			Assets:       len(assets),
+			SigmaPerRule: make(map[string]uint),
+		},
 	}, nil
 }
@@ -0,0 +1,31 @@
+package intel
+
+import (
+	"errors"
+
+	"github.com/ccdcoe/go-peek/pkg/intel/wise"
+	"github.com/ccdcoe/go-peek/pkg/persist"
+)
+
+// Config configures a GlobalCache.
+type Config struct {
+	// Prune enables the periodic sweep of stale, non-asset entries from
+	// the in-memory read cache.
+	Prune bool
+	// DumpJSONAssets, when set, is the path DumpJSONAssets writes a flat
+	// JSONL export of known assets to on request.
+	DumpJSONAssets string
+	// Wise is optional; when set, cache misses fall back to a Moloch WISE
+	// lookup.
+	Wise *wise.Config
+	// Persist is the badgerdb handle assets are read from on startup and
+	// written through to on cache misses.
+	Persist *persist.Badger
+}
+
+func (c Config) Validate() error {
+	if c.Persist == nil {
+		return errors.New("intel: missing badgerdb persistence")
+	}
+	return nil
+}
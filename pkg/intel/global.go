@@ -1,9 +1,9 @@
 package intel
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	"encoding/json"
+	"encoding/gob"
 	"fmt"
 	"net"
 	"os"
@@ -12,14 +12,12 @@ import (
 
 	"github.com/ccdcoe/go-peek/pkg/intel/wise"
 	"github.com/ccdcoe/go-peek/pkg/models/meta"
+	"github.com/ccdcoe/go-peek/pkg/persist"
 	"github.com/ccdcoe/go-peek/pkg/utils"
 	log "github.com/sirupsen/logrus"
 )
 
-type persist struct {
-	dump   time.Duration
-	assets string
-}
+const badgerPrefix = "assets"
 
 // Global is a caching container that is meant to be thread safe
 // should ask from external sources if entry is missing
@@ -28,7 +26,8 @@ type GlobalCache struct {
 	assets   *sync.Map
 	networks *sync.Map
 
-	persist
+	db         *persist.Badger
+	dumpAssets string
 
 	prune
 	wise    *wise.Handle
@@ -47,6 +46,7 @@ func NewGlobalCache(c *Config) (*GlobalCache, error) {
 	gc := &GlobalCache{
 		assets:   &sync.Map{},
 		networks: &sync.Map{},
+		db:       c.Persist,
 		prune: prune{
 			enabled: func() bool {
 				if c.Prune {
@@ -57,52 +57,49 @@ func NewGlobalCache(c *Config) (*GlobalCache, error) {
 			interval: 30 * time.Second,
 			period:   120 * time.Second,
 		},
-		ctx:     ctx,
-		stopper: cancel,
-		wg:      &sync.WaitGroup{},
-		Errs:    &utils.ErrChan{Max: 100},
-		persist: persist{
-			dump: 5 * time.Second,
-		},
+		ctx:        ctx,
+		stopper:    cancel,
+		wg:         &sync.WaitGroup{},
+		Errs:       &utils.ErrChan{Max: 100},
+		dumpAssets: c.DumpJSONAssets,
 	}
-	if c.DumpJSONAssets != "" {
-		file, err := os.Stat(c.DumpJSONAssets)
-		if err != nil {
+
+	var loaded int
+	for record := range gc.db.Scan(badgerPrefix) {
+		var obj Asset
+		buf := bytes.NewBuffer(record.Data)
+		if err := gob.NewDecoder(buf).Decode(&obj); err != nil {
 			return nil, err
 		}
-		if file.IsDir() {
-			return nil, fmt.Errorf("CSV asset dump path %s is dir, but should be regular file", file.Name())
-		}
-		gc.persist.assets = c.DumpJSONAssets
-		log.Tracef("Setting up asset persistence in %s", gc.persist.assets)
-		if !utils.FileNotExists(gc.persist.assets) {
-			f, err := os.Open(gc.persist.assets)
-			if err != nil {
-				return nil, err
-			}
-			count := 0
-			scanner := bufio.NewScanner(f)
-			for scanner.Scan() {
-				var obj Asset
-				if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
-					return nil, err
-				}
-				obj.Update()
-				gc.assets.Store(obj.Data.IP.String(), obj)
-				count++
+		obj.Update()
+
+		// A confirmed asset's own IP is the same key GetIP stored it under.
+		// Prefer deriving it from the decoded value over trusting
+		// record.Key outright, and warn loudly rather than silently
+		// mis-keying (and so losing) the whole read cache if badger ever
+		// returns it without the Set-time prefix stripped.
+		key := record.Key
+		if obj.IsAsset {
+			if derived := obj.Data.IP.String(); derived != "" && derived != key {
+				log.Warnf(
+					"global asset cache: badgerdb scan key %q does not match asset IP %q, trusting the asset's own identity",
+					key, derived)
+				key = derived
 			}
-			f.Close()
-			log.Tracef("loaded %d assets from %s", count, gc.persist.assets)
 		}
+		gc.assets.Store(key, obj)
+		loaded++
 	}
+	log.Tracef("loaded %d assets from badgerdb", loaded)
+
 	go func() {
 		log.Tracef("spawning global asset cache housekeeper thread")
 		gc.wg.Add(1)
 		defer gc.wg.Done()
+		tick := time.NewTicker(gc.prune.interval)
+		defer tick.Stop()
 	loop:
 		for {
-			tick := time.NewTicker(gc.prune.interval)
-			dump := time.NewTicker(gc.persist.dump)
 			select {
 			case <-gc.ctx.Done():
 				break loop
@@ -110,7 +107,7 @@ func NewGlobalCache(c *Config) (*GlobalCache, error) {
 				if !gc.prune.enabled {
 					continue loop
 				}
-				log.Tracef("global assset cache pruning executed")
+				log.Tracef("global asset cache pruning executed")
 				now := time.Now()
 				var count, total int
 				gc.assets.Range(func(k, v interface{}) bool {
@@ -132,47 +129,8 @@ func NewGlobalCache(c *Config) (*GlobalCache, error) {
 					return true
 				})
 				log.Tracef(
-					"pruned %d expired items from global asset cache, now has %d items",
+					"pruned %d expired items from in-memory read cache of %d items",
 					count, total)
-			case <-dump.C:
-				if gc.persist.assets == "" {
-					continue loop
-				}
-				log.Tracef("dumping assets to %s", gc.persist.assets)
-				stuff := make([]Asset, 0)
-				gc.assets.Range(func(k, v interface{}) bool {
-					switch a := v.(type) {
-					case Asset:
-						if a.IsAsset {
-							stuff = append(stuff, a)
-						}
-					case *Asset:
-						if a.IsAsset {
-							stuff = append(stuff, *a)
-						}
-					}
-					return true
-				})
-				if len(stuff) == 0 {
-					log.Trace("No stuff to dump, continuing")
-					continue loop
-				}
-				f, err := os.Create(gc.persist.assets)
-				if err != nil {
-					gc.Errs.Send(err)
-					continue loop
-				}
-				for _, a := range stuff {
-					fmt.Fprintf(os.Stdout, "%+v\n", a)
-					j, err := a.JSON()
-					if err != nil {
-						panic(err)
-					}
-					if err == nil {
-						fmt.Fprintf(f, "%s\n", string(j))
-					}
-				}
-				f.Close()
 			}
 		}
 		log.Tracef("global asset cache housekeeper exited correctly")
@@ -210,26 +168,86 @@ func (g GlobalCache) GetIP(key net.IP) (*Asset, bool) {
 	}
 
 	asset := &Asset{updated: time.Now()}
-	if g.wise == nil {
-		return asset, false
-	}
-	if a, ok, err := wise.GetAsset(
-		*g.wise,
-		key,
-		FieldPrefix+".original",
-		FieldPrefix+".pretty",
-		FieldPrefix+".kernel",
-	); err != nil {
-		g.Errs.Send(err)
-	} else if ok {
-		asset.Data = a
-		asset.IsAsset = true
-		//g.updateAllNets()
+	if g.wise != nil {
+		if a, ok, err := wise.GetAsset(
+			*g.wise,
+			key,
+			FieldPrefix+".original",
+			FieldPrefix+".pretty",
+			FieldPrefix+".kernel",
+		); err != nil {
+			g.Errs.Send(err)
+		} else if ok {
+			asset.Data = a
+			asset.IsAsset = true
+			//g.updateAllNets()
+		}
 	}
+
 	g.assets.Store(key.String(), asset)
+	g.writeThrough(key.String(), asset)
 	return asset, false
 }
 
+// writeThrough persists a freshly-resolved asset to badger on a cache miss.
+// Confirmed assets (IsAsset) are written with no expiry; placeholders for
+// keys WISE had nothing for get a TTL equal to the prune period so they age
+// out of badger the same way they already age out of the in-memory read
+// cache.
+func (g GlobalCache) writeThrough(key string, asset *Asset) {
+	if g.db == nil {
+		return
+	}
+	value := persist.GenericValue{Key: key, Data: *asset}
+	if asset.IsAsset {
+		g.db.Set(badgerPrefix, value)
+		return
+	}
+	g.db.SetTTL(badgerPrefix, value, g.prune.period)
+}
+
+// DumpJSONAssets writes every known asset out to the path configured via
+// Config.DumpJSONAssets, preserving the pre-badger flat-file export for
+// operators who still want a JSONL snapshot. Unlike the old housekeeper
+// dump branch this is no longer run automatically; call it explicitly
+// (e.g. from a CLI flag or signal handler) when an export is needed.
+func (g *GlobalCache) DumpJSONAssets() error {
+	if g.dumpAssets == "" {
+		return nil
+	}
+	f, err := os.Create(g.dumpAssets)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var count int
+	g.assets.Range(func(k, v interface{}) bool {
+		var a Asset
+		switch val := v.(type) {
+		case Asset:
+			a = val
+		case *Asset:
+			a = *val
+		default:
+			return true
+		}
+		if !a.IsAsset {
+			return true
+		}
+		j, err := a.JSON()
+		if err != nil {
+			g.Errs.Send(err)
+			return true
+		}
+		fmt.Fprintf(f, "%s\n", string(j))
+		count++
+		return true
+	})
+	log.Tracef("dumped %d assets to %s", count, g.dumpAssets)
+	return nil
+}
+
 func (g GlobalCache) updateAllNets() int {
 	var updated int
 	g.assets.Range(func(assetKey, assetData interface{}) bool {
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) handleIP(w http.ResponseWriter, r *http.Request) {
+	ip := net.ParseIP(mux.Vars(r)["ip"])
+	if ip == nil {
+		http.Error(w, "invalid ip", http.StatusBadRequest)
+		return
+	}
+	s.respond(w, s.lookup(ip.String()))
+}
+
+func (s *Server) handleHost(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, s.lookup(mux.Vars(r)["host"]))
+}
+
+type bulkRequest struct {
+	Keys []string `json:"keys"`
+}
+
+// handleBulk is the WISE `/get` endpoint: a single POST carrying every key
+// a caller wants resolved in one round trip, returned keyed by the
+// requested key.
+func (s *Server) handleBulk(w http.ResponseWriter, r *http.Request) {
+	var req bulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	out := make(map[string]map[string]string, len(req.Keys))
+	for _, key := range req.Keys {
+		out[key] = s.lookup(key)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+func (s *Server) respond(w http.ResponseWriter, fields map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	if fields == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fields = map[string]string{}
+	}
+	json.NewEncoder(w).Encode(fields)
+}
+
+// lookup resolves key (an IP or hostname) against the enrich handler's
+// asset inventory first, falling back to the global cache, and maps the
+// hit onto the configured WISE field set. A nil map means no hit.
+func (s *Server) lookup(key string) map[string]string {
+	if s.config.Enrich != nil {
+		if record, ok := s.config.Enrich.Asset(key); ok {
+			return mapFields(record, s.config.Fields)
+		}
+	}
+	if s.config.Global != nil {
+		if ip := net.ParseIP(key); ip != nil {
+			// GetIP's bool return only reports whether the key was already
+			// cached before this call; on a cache miss it still resolves
+			// (and stores) a real asset via its WISE fallback, so a hit
+			// must be judged on the asset itself, not that return value.
+			if asset, _ := s.config.Global.GetIP(ip); asset != nil && asset.IsAsset {
+				return mapFields(asset.Data, s.config.Fields)
+			}
+		}
+	}
+	return nil
+}
+
+// mapFields flattens v (a providentia.Record or meta.Asset, depending on
+// which backing store answered the lookup) to its JSON representation and
+// projects out just the configured field names, so --wise-server-fields
+// controls exactly what gets shipped to WISE-aware consumers. An empty
+// fields list returns everything found.
+func mapFields(v interface{}, fields []string) map[string]string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil
+	}
+
+	if len(fields) == 0 {
+		out := make(map[string]string, len(all))
+		for k, val := range all {
+			out[k] = fmt.Sprintf("%v", val)
+		}
+		return out
+	}
+	out := make(map[string]string, len(fields))
+	for _, f := range fields {
+		if val, ok := all[f]; ok {
+			out[f] = fmt.Sprintf("%v", val)
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+// Package server implements the Moloch/Arkime WISE HTTP lookup protocol on
+// top of go-peek's own live asset inventory, so WISE-aware tools (Arkime,
+// Suricata via lua) can query go-peek the same way they'd query upstream
+// WISE.
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go-peek/pkg/enrich"
+	"go-peek/pkg/intel"
+)
+
+// Config configures a Server.
+type Config struct {
+	Listen string
+	// Fields is the set of WISE field names to return per hit. An empty
+	// slice returns every field found on the underlying asset.
+	Fields []string
+
+	TLSCert string
+	TLSKey  string
+
+	BasicUser string
+	BasicPass string
+
+	// Enrich and Global are both optional, but at least one must be set.
+	// Enrich is consulted first, falling back to Global on a miss.
+	Enrich *enrich.Handler
+	Global *intel.GlobalCache
+}
+
+func (c Config) Validate() error {
+	if c.Listen == "" {
+		return errors.New("wise server: missing listen address")
+	}
+	if c.Enrich == nil && c.Global == nil {
+		return errors.New("wise server: need at least one of enrich handler or global cache")
+	}
+	return nil
+}
+
+// Server exposes go-peek's asset inventory over the WISE HTTP protocol.
+type Server struct {
+	config Config
+	http   *http.Server
+}
+
+func New(c Config) (*Server, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Server{config: c}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/ip/{ip}", s.handleIP).Methods(http.MethodGet)
+	router.HandleFunc("/host/{host}", s.handleHost).Methods(http.MethodGet)
+	router.HandleFunc("/get", s.handleBulk).Methods(http.MethodPost)
+
+	var handler http.Handler = router
+	if c.BasicUser != "" {
+		handler = basicAuth(handler, c.BasicUser, c.BasicPass)
+	}
+
+	s.http = &http.Server{
+		Addr:    c.Listen,
+		Handler: handler,
+	}
+	return s, nil
+}
+
+// Run starts serving and blocks until ctx is cancelled or the listener
+// fails, matching the lifecycle of the other long-running components
+// started alongside the enrich loop.
+func (s *Server) Run(ctx context.Context) error {
+	errs := make(chan error, 1)
+	go func() {
+		if s.config.TLSCert != "" {
+			errs <- s.http.ListenAndServeTLS(s.config.TLSCert, s.config.TLSKey)
+			return
+		}
+		errs <- s.http.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return s.http.Close()
+	case err := <-errs:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	}
+}
+
+func basicAuth(next http.Handler, user, pass string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != user || p != pass {
+			w.Header().Set("WWW-Authenticate", `Basic realm="wise"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
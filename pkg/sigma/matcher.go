@@ -0,0 +1,141 @@
+package sigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// matcher is a compiled predicate over an event's flattened JSON fields.
+type matcher func(fields map[string]interface{}) bool
+
+// compileDetection turns a Sigma `detection` block (named selections plus a
+// `condition` string) into a single matcher. Only the subset of the Sigma
+// condition grammar this module's rulesets actually use is supported:
+// selection names combined with "and", "or" and a leading "not". Selectors
+// requiring aggregation ("1 of them", counting, ...) are not implemented.
+func compileDetection(detection map[string]interface{}) (matcher, error) {
+	conditionRaw, ok := detection["condition"]
+	if !ok {
+		return nil, fmt.Errorf("detection block missing condition")
+	}
+	condition, ok := conditionRaw.(string)
+	if !ok {
+		return nil, fmt.Errorf("condition must be a string")
+	}
+
+	selections := make(map[string]matcher)
+	for name, raw := range detection {
+		if name == "condition" {
+			continue
+		}
+		sel, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("selection %q is not a mapping", name)
+		}
+		selections[name] = compileSelection(sel)
+	}
+
+	return func(fields map[string]interface{}) bool {
+		return evalCondition(condition, selections, fields)
+	}, nil
+}
+
+// compileSelection builds a matcher requiring every field in sel to be
+// present on the event and contain at least one of the configured values as
+// a substring, matching Sigma's loose default field-matching behaviour. A
+// list-valued selector (e.g. `EventID: [1, 3, 5]`) is an OR across its
+// elements, as Sigma specifies. Only scalar and scalar-list selection
+// values are supported; a selection written as a list of mappings (an OR
+// group of full selections) is rejected by compileDetection as not a
+// mapping.
+func compileSelection(sel map[interface{}]interface{}) matcher {
+	type clause struct {
+		field string
+		want  []string
+	}
+	clauses := make([]clause, 0, len(sel))
+	for k, v := range sel {
+		clauses = append(clauses, clause{
+			field: fmt.Sprintf("%v", k),
+			want:  selectionValues(v),
+		})
+	}
+	return func(fields map[string]interface{}) bool {
+		for _, c := range clauses {
+			val, ok := fields[c.field]
+			if !ok {
+				return false
+			}
+			got := strings.ToLower(fmt.Sprintf("%v", val))
+			matched := false
+			for _, want := range c.want {
+				if strings.Contains(got, want) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// selectionValues lowercases a selection value for substring comparison,
+// expanding a []interface{} into one entry per element so a list-valued
+// selector matches on any element rather than stringifying the whole list.
+func selectionValues(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return []string{strings.ToLower(fmt.Sprintf("%v", v))}
+	}
+	want := make([]string, 0, len(list))
+	for _, elem := range list {
+		want = append(want, strings.ToLower(fmt.Sprintf("%v", elem)))
+	}
+	return want
+}
+
+// evalCondition walks a whitespace-tokenized condition string left to
+// right, applying "and"/"or" between named selections and negating the
+// next term on "not".
+func evalCondition(condition string, selections map[string]matcher, fields map[string]interface{}) bool {
+	tokens := strings.Fields(condition)
+
+	var (
+		result  bool
+		op      = "and"
+		negate  bool
+		started bool
+	)
+	for _, tok := range tokens {
+		switch strings.ToLower(tok) {
+		case "not":
+			negate = true
+			continue
+		case "and", "or":
+			op = strings.ToLower(tok)
+			continue
+		}
+
+		sel, ok := selections[tok]
+		val := ok && sel(fields)
+		if negate {
+			val = !val
+			negate = false
+		}
+
+		if !started {
+			result = val
+			started = true
+			continue
+		}
+		if op == "or" {
+			result = result || val
+		} else {
+			result = result && val
+		}
+	}
+	return result
+}
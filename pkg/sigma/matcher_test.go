@@ -0,0 +1,78 @@
+package sigma
+
+import "testing"
+
+func TestCompileSelectionScalar(t *testing.T) {
+	sel := compileSelection(map[interface{}]interface{}{
+		"Image": `C:\Windows\System32\cmd.exe`,
+	})
+	cases := []struct {
+		name   string
+		fields map[string]interface{}
+		want   bool
+	}{
+		{"substring match", map[string]interface{}{"Image": `C:\Windows\System32\cmd.exe`}, true},
+		{"case insensitive", map[string]interface{}{"Image": `c:\windows\system32\CMD.exe`}, true},
+		{"missing field", map[string]interface{}{"OtherField": "x"}, false},
+		{"no match", map[string]interface{}{"Image": `C:\Windows\System32\notepad.exe`}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := sel(c.fields); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCompileSelectionList(t *testing.T) {
+	sel := compileSelection(map[interface{}]interface{}{
+		"EventID": []interface{}{1, 3, 5},
+	})
+	cases := []struct {
+		name   string
+		fields map[string]interface{}
+		want   bool
+	}{
+		{"matches first element", map[string]interface{}{"EventID": 1}, true},
+		{"matches middle element", map[string]interface{}{"EventID": 3}, true},
+		{"matches last element", map[string]interface{}{"EventID": 5}, true},
+		{"matches none", map[string]interface{}{"EventID": 7}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := sel(c.fields); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestEvalCondition(t *testing.T) {
+	selections := map[string]matcher{
+		"sel_true":  func(map[string]interface{}) bool { return true },
+		"sel_false": func(map[string]interface{}) bool { return false },
+	}
+	cases := []struct {
+		name      string
+		condition string
+		want      bool
+	}{
+		{"single true", "sel_true", true},
+		{"single false", "sel_false", false},
+		{"and both true", "sel_true and sel_true", true},
+		{"and one false", "sel_true and sel_false", false},
+		{"or one true", "sel_false or sel_true", true},
+		{"or both false", "sel_false or sel_false", false},
+		{"not true", "not sel_true", false},
+		{"not false", "not sel_false", true},
+		{"unknown selection", "sel_missing", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := evalCondition(c.condition, selections, nil); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
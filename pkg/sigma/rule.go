@@ -0,0 +1,62 @@
+package sigma
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"go-peek/pkg/models/events"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule is a single compiled Sigma rule, scoped to the events.Atomic kind
+// its ruleset file was registered under.
+type Rule struct {
+	ID    string
+	Title string
+	Kind  events.Atomic
+
+	match matcher
+}
+
+type ruleYAML struct {
+	ID        string                 `yaml:"id"`
+	Title     string                 `yaml:"title"`
+	Detection map[string]interface{} `yaml:"detection"`
+}
+
+// loadRules parses every `---`-separated document in a Sigma YAML file,
+// compiling each one's detection block for the given event kind.
+func loadRules(path string, kind events.Atomic) ([]*Rule, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	var rules []*Rule
+	for {
+		var doc ruleYAML
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("sigma: %s: %w", path, err)
+		}
+		if doc.ID == "" {
+			continue
+		}
+		match, err := compileDetection(doc.Detection)
+		if err != nil {
+			return nil, fmt.Errorf("sigma: %s: rule %s: %w", path, doc.ID, err)
+		}
+		rules = append(rules, &Rule{
+			ID:    doc.ID,
+			Title: doc.Title,
+			Kind:  kind,
+			match: match,
+		})
+	}
+	return rules, nil
+}
@@ -0,0 +1,165 @@
+package sigma
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"go-peek/pkg/models/events"
+)
+
+// Config configures a Ruleset.
+type Config struct {
+	// RulesetPaths are "kind:path" pairs, e.g. "suricata:/etc/sigma/net.yml",
+	// as produced by app.FlagSigmaRulesetPaths.
+	RulesetPaths []string
+}
+
+func (c Config) Validate() error {
+	if len(c.RulesetPaths) == 0 {
+		return errors.New("sigma: missing ruleset paths")
+	}
+	return nil
+}
+
+// Hit describes a single rule match against a decoded event.
+type Hit struct {
+	RuleID string
+	Title  string
+	Kind   events.Atomic
+}
+
+// Ruleset holds every compiled rule, grouped by the events.Atomic kind it
+// applies to, and hot-reloads a kind's rules whenever its source file
+// changes on disk.
+type Ruleset struct {
+	mu    sync.RWMutex
+	rules map[events.Atomic][]*Rule
+	paths map[string]events.Atomic
+
+	watcher *fsnotify.Watcher
+}
+
+func New(c Config) (*Ruleset, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	rs := &Ruleset{
+		rules: make(map[events.Atomic][]*Rule),
+		paths: make(map[string]events.Atomic),
+	}
+	for _, entry := range c.RulesetPaths {
+		kind, path, err := splitRulesetEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		if err := rs.load(kind, path); err != nil {
+			return nil, err
+		}
+		rs.paths[path] = kind
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for path := range rs.paths {
+		if err := watcher.Add(path); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	rs.watcher = watcher
+	go rs.watch()
+
+	return rs, nil
+}
+
+func splitRulesetEntry(entry string) (events.Atomic, string, error) {
+	parts := strings.SplitN(entry, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("sigma: malformed ruleset entry %q, want kind:path", entry)
+	}
+	return events.Atomic(parts[0]), parts[1], nil
+}
+
+func (rs *Ruleset) load(kind events.Atomic, path string) error {
+	rules, err := loadRules(path, kind)
+	if err != nil {
+		return err
+	}
+	rs.mu.Lock()
+	rs.rules[kind] = rules
+	rs.mu.Unlock()
+	return nil
+}
+
+// watch reloads a ruleset file whenever fsnotify reports it changed, so
+// operators can push new detections without restarting the pipeline.
+func (rs *Ruleset) watch() {
+	for {
+		select {
+		case event, ok := <-rs.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			kind, ok := rs.paths[event.Name]
+			if !ok {
+				continue
+			}
+			if err := rs.load(kind, event.Name); err != nil {
+				log.Errorf("sigma: failed to reload ruleset %s: %s", event.Name, err)
+				continue
+			}
+			log.Infof("sigma: reloaded ruleset %s", event.Name)
+		case err, ok := <-rs.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Errorf("sigma: ruleset watcher error: %s", err)
+		}
+	}
+}
+
+func (rs *Ruleset) Close() error {
+	return rs.watcher.Close()
+}
+
+// Match evaluates every rule registered for kind and returns one Hit per
+// rule whose detection logic matched the event's JSON representation.
+func (rs *Ruleset) Match(event events.GameEvent, kind events.Atomic) ([]Hit, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return rs.MatchRaw(raw, kind)
+}
+
+// MatchRaw is Match for callers that already have the event's raw JSON and
+// no decoded events.GameEvent to hand, e.g. the sigma test CLI exercising a
+// ruleset in CI.
+func (rs *Ruleset) MatchRaw(raw []byte, kind events.Atomic) ([]Hit, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	rs.mu.RLock()
+	rules := rs.rules[kind]
+	rs.mu.RUnlock()
+
+	var hits []Hit
+	for _, rule := range rules {
+		if rule.match(fields) {
+			hits = append(hits, Hit{RuleID: rule.ID, Title: rule.Title, Kind: kind})
+		}
+	}
+	return hits, nil
+}
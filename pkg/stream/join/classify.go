@@ -0,0 +1,32 @@
+package join
+
+import (
+	"go-peek/pkg/enrich"
+	"go-peek/pkg/models/events"
+)
+
+// classify returns the events.Atomic a decoded event most likely
+// corresponds to, mirroring enrich.Handler.Decode's raw-to-kind mapping in
+// reverse. Windows event log kinds (EventLogE, SysmonE) share a concrete Go
+// type, so an ambiguous DynamicWinlogbeat resolves to whichever of the
+// Joiner's two configured kinds is events.SysmonE. An enrich.Tagged event
+// is classified by the event it wraps.
+func (j *Joiner) classify(event events.GameEvent) events.Atomic {
+	if tagged, ok := event.(enrich.Tagged); ok {
+		event = tagged.GameEvent
+	}
+	switch event.(type) {
+	case *events.Suricata:
+		return events.SuricataE
+	case *events.Syslog:
+		return events.SyslogE
+	case *events.Snoopy:
+		return events.SnoopyE
+	case *events.DynamicWinlogbeat:
+		if j.left == events.SysmonE || j.right == events.SysmonE {
+			return events.SysmonE
+		}
+		return events.EventLogE
+	}
+	return ""
+}
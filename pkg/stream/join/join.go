@@ -0,0 +1,221 @@
+// Package join correlates events of two kinds sharing a join key within a
+// bounded time window, e.g. Suricata flows joined with Sysmon
+// network-connection events (EventID 3) to attach process identity
+// (Image, ProcessGuid) to a network alert.
+package join
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-peek/pkg/models/events"
+	"go-peek/pkg/persist"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// KeyFunc extracts the join key from an event, e.g. a Suricata flow_id or a
+// 5-tuple built from source/destination IP and port.
+type KeyFunc func(events.GameEvent) string
+
+// Joined wraps two correlated events. Embedding the primary side's
+// events.GameEvent lets a Joined value satisfy that interface as-is for
+// any downstream code (enrich.Handler.Enrich, a Sigma ruleset, an output
+// sink) while keeping both sides of the join available to callers that
+// want them.
+type Joined struct {
+	events.GameEvent
+
+	Left  events.GameEvent
+	Right events.GameEvent
+
+	// LeftOnly/RightOnly is set when Process emitted this event because
+	// the join window elapsed before a partner arrived.
+	LeftOnly  bool
+	RightOnly bool
+}
+
+// half is one side of an in-flight join, persisted so it survives a
+// restart.
+type half struct {
+	Kind    events.Atomic
+	Event   events.GameEvent
+	Arrived time.Time
+}
+
+// Joiner holds whichever half of a join has arrived, keyed by KeyFunc,
+// until its partner shows up or the window elapses.
+type Joiner struct {
+	left, right events.Atomic
+	key         KeyFunc
+	window      time.Duration
+
+	db *persist.Badger
+
+	mu      sync.Mutex
+	pending map[string]half
+}
+
+// defaultWindow is substituted for a non-positive window, which would
+// otherwise make Process panic when it creates its ticker.
+const defaultWindow = time.Minute
+
+// New builds a Joiner for events of leftKind and rightKind. Call
+// WithPersist before Process to make in-flight joins survive a restart.
+func New(leftKind, rightKind events.Atomic, keyFn KeyFunc, window time.Duration) *Joiner {
+	if window <= 0 {
+		log.Warnf("stream join: window %s is not positive, defaulting to %s", window, defaultWindow)
+		window = defaultWindow
+	}
+	return &Joiner{
+		left:    leftKind,
+		right:   rightKind,
+		key:     keyFn,
+		window:  window,
+		pending: make(map[string]half),
+	}
+}
+
+// WithPersist backs the Joiner with a Badger state store and replays
+// whatever halves were still pending when the process last stopped, so
+// in-flight joins survive a restart.
+func (j *Joiner) WithPersist(db *persist.Badger) (*Joiner, error) {
+	j.db = db
+	for record := range db.Scan(badgerPrefix) {
+		h, err := decodeHalf(record.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		// The join key is recomputed from the decoded event itself rather
+		// than trusted from record.Key, so a badger Scan that doesn't
+		// round-trip the bare Set-time key can't silently mis-key (and so
+		// drop) an in-flight join on restart.
+		key := record.Key
+		if derived := j.key(h.Event); derived != "" && derived != key {
+			log.Warnf(
+				"stream join: badgerdb scan key %q does not match the event's own join key %q, trusting the event",
+				key, derived)
+			key = derived
+		}
+		j.pending[key] = h
+	}
+	return j, nil
+}
+
+// Process reads decoded events from in, holds each one until its partner
+// with the same key arrives or window elapses, and emits joined (or
+// left-only/right-only) events on the returned channel. The channel closes
+// once in is drained and every pending half has resolved.
+func (j *Joiner) Process(ctx context.Context, in <-chan events.GameEvent) <-chan events.GameEvent {
+	out := make(chan events.GameEvent)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(j.window / 2)
+		defer ticker.Stop()
+
+		drained := false
+		for {
+			if drained && j.count() == 0 {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-in:
+				if !ok {
+					drained = true
+					in = nil
+					continue
+				}
+				if !j.ingest(ctx, event, out) {
+					return
+				}
+			case <-ticker.C:
+				if !j.expire(ctx, out) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func (j *Joiner) count() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.pending)
+}
+
+// ingest folds event into the pending state, emitting a Joined event on a
+// match. It reports false if ctx was cancelled while blocked on the send,
+// so Process can stop promptly instead of leaking if out is never drained.
+func (j *Joiner) ingest(ctx context.Context, event events.GameEvent, out chan<- events.GameEvent) bool {
+	kind := j.classify(event)
+	if kind != j.left && kind != j.right {
+		return true
+	}
+	key := j.key(event)
+
+	j.mu.Lock()
+	partner, ok := j.pending[key]
+	if !ok || partner.Kind == kind {
+		// No partner yet, or a second event on the same side inside the
+		// window; keep the newest arrival on this side.
+		h := half{Kind: kind, Event: event, Arrived: time.Now()}
+		j.pending[key] = h
+		j.persistHalf(key, h)
+		j.mu.Unlock()
+		return true
+	}
+	delete(j.pending, key)
+	j.mu.Unlock()
+
+	j.deleteHalf(key)
+
+	joined := Joined{GameEvent: event, Left: partner.Event, Right: event}
+	if kind == j.left {
+		joined = Joined{GameEvent: partner.Event, Left: event, Right: partner.Event}
+	}
+	select {
+	case out <- joined:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// expire flushes every pending half whose window has elapsed as a
+// left-only or right-only Joined event, so downstream Sigma rules can
+// alert on flows or connections that never found a partner. It reports
+// false if ctx was cancelled while blocked on a send.
+func (j *Joiner) expire(ctx context.Context, out chan<- events.GameEvent) bool {
+	cutoff := time.Now().Add(-j.window)
+
+	j.mu.Lock()
+	stale := make(map[string]half)
+	for key, h := range j.pending {
+		if h.Arrived.Before(cutoff) {
+			stale[key] = h
+			delete(j.pending, key)
+		}
+	}
+	j.mu.Unlock()
+
+	for key, h := range stale {
+		j.deleteHalf(key)
+		joined := Joined{GameEvent: h.Event, LeftOnly: h.Kind == j.left, RightOnly: h.Kind != j.left}
+		if joined.LeftOnly {
+			joined.Left = h.Event
+		} else {
+			joined.Right = h.Event
+		}
+		select {
+		case out <- joined:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package join
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"go-peek/pkg/models/events"
+	"go-peek/pkg/persist"
+)
+
+const badgerPrefix = "stream-join"
+
+func init() {
+	gob.Register(&events.Suricata{})
+	gob.Register(&events.DynamicWinlogbeat{})
+	gob.Register(&events.Syslog{})
+	gob.Register(&events.Snoopy{})
+}
+
+// persistHalf writes a pending half through to badger with a TTL equal to
+// the join window, so a half that's never matched ages out of the state
+// store the same way it ages out of the in-memory map.
+func (j *Joiner) persistHalf(key string, h half) {
+	if j.db == nil {
+		return
+	}
+	j.db.SetTTL(badgerPrefix, persist.GenericValue{Key: key, Data: h}, j.window)
+}
+
+func (j *Joiner) deleteHalf(key string) {
+	if j.db == nil {
+		return
+	}
+	j.db.Delete(badgerPrefix, key)
+}
+
+func decodeHalf(raw []byte) (half, error) {
+	var h half
+	buf := bytes.NewBuffer(raw)
+	err := gob.NewDecoder(buf).Decode(&h)
+	return h, err
+}